@@ -27,21 +27,26 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"strings"
 	"sync"
 	"time"
 	"unicode/utf8"
 
-	"golang.org/x/crypto/ssh/terminal"
-
 	"github.com/sirupsen/logrus"
 
 	"github.com/loadimpact/k6/core/local"
 	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/ui/pb"
+	"github.com/loadimpact/k6/ui/term"
 )
 
+// ansiSupported reports whether stdout can be expected to render ANSI
+// escape sequences, on this OS and in this terminal. It's resolved once at
+// package init, same as the other output-related globals in this file.
+var ansiSupported = term.SupportsANSI(os.Stdout.Fd())
+
 // UIMode defines various rendering methods
 type UIMode uint8
 
@@ -52,6 +57,9 @@ const (
 	UIModeResponsive UIMode = iota + 1
 	UIModeCompact
 	UIModeFull
+	// UIModeInteractive renders a full-screen, keyboard-driven dashboard
+	// instead of the line-based renderer, when stdout is a TTY.
+	UIModeInteractive
 )
 
 // Decode implements envconfig.Decoder
@@ -72,9 +80,8 @@ type consoleWriter struct {
 
 func (w *consoleWriter) Write(p []byte) (n int, err error) {
 	origLen := len(p)
-	if w.IsTTY {
+	if w.IsTTY && ansiSupported {
 		// Add a TTY code to erase till the end of line with each new line
-		// TODO: check how cross-platform this is...
 		p = bytes.Replace(p, []byte{'\n'}, []byte{'\x1b', '[', '0', 'K', '\n'}, -1)
 	}
 
@@ -93,25 +100,22 @@ func (w *consoleWriter) Write(p []byte) (n int, err error) {
 
 func printBar(bar *pb.ProgressBar, rightText string) {
 	end := "\n"
-	if stdout.IsTTY {
+	if stdout.IsTTY && ansiSupported {
 		// If we're in a TTY, instead of printing the bar and going to the next
 		// line, erase everything till the end of the line and return to the
 		// start, so that the next print will overwrite the same line.
-		//
-		// TODO: check for cross platform support
 		end = "\x1b[0K\r"
 	}
 	rendered := bar.Render(0, 0)
 	// Only output the left and middle part of the progress bar
-	fprintf(stdout, "%s %s %s%s", rendered.Left, rendered.Progress(), rightText, end)
+	fprintf(stdout, "%s %s %s%s", rendered.Left, rendered.Bar(), rightText, end)
 }
 
 func renderMultipleBars(
 	isTTY, goBack bool, maxLeft, widthDelta int, pbs []*pb.ProgressBar,
 ) (string, int) {
 	lineEnd := "\n"
-	if isTTY {
-		//TODO: check for cross platform support
+	if isTTY && ansiSupported {
 		lineEnd = "\x1b[K\n" // erase till end of line
 	}
 
@@ -162,24 +166,23 @@ func renderMultipleBars(
 			rightText += fmt.Sprintf(rightPadFmt, rend.Right[i])
 		}
 		// Get visible line length, without ANSI escape sequences (color)
-		status := fmt.Sprintf(" %s ", rend.Status())
-		line := leftText + status + rend.Progress() + rightText
+		status := fmt.Sprintf(" %s ", rend.StatusGlyph())
+		line := leftText + status + rend.Bar() + rightText
 		lineRuneLen := utf8.RuneCountInString(line)
 		if lineRuneLen > longestLine {
 			longestLine = lineRuneLen
 		}
 		if !noColor {
 			rend.Color = true
-			status = fmt.Sprintf(" %s ", rend.Status())
+			status = fmt.Sprintf(" %s ", rend.StatusGlyph())
 			line = fmt.Sprintf(leftPadFmt+"%s%s%s",
-				rend.Left, status, rend.Progress(), rightText)
+				rend.Left, status, rend.Bar(), rightText)
 		}
 		result[i+1] = line + lineEnd
 	}
 
-	if isTTY && goBack {
+	if isTTY && ansiSupported && goBack {
 		// Go back to the beginning
-		//TODO: check for cross platform support
 		result[pbsCount+1] = fmt.Sprintf("\r\x1b[%dA", pbsCount+1)
 	} else {
 		result[pbsCount+1] = "\n"
@@ -188,6 +191,68 @@ func renderMultipleBars(
 	return strings.Join(result, ""), longestLine
 }
 
+// ProgressRenderer draws the progress bars of the running executors to the
+// screen. It's the extension point that lets showProgress swap the default
+// line-based output for richer renderers (e.g. the full-screen interactive
+// dashboard) without duplicating the polling/resize machinery below.
+type ProgressRenderer interface {
+	// Render draws a frame. goBack tells the renderer whether it may rely
+	// on cursor movement to overwrite its previous frame (false is used
+	// for the final draw, after which the cursor is left where it is).
+	Render(goBack bool)
+	// Resize is called whenever the terminal size changes, so the
+	// renderer can recompute its layout before the next Render.
+	Resize(termWidth, termHeight int)
+	// Stop releases any resources the renderer holds (raw terminal mode,
+	// background goroutines, open sockets, etc). It's called once, after
+	// the last Render.
+	Stop()
+}
+
+// lineRenderer is the original, default ProgressRenderer: it prints one
+// line per executor and, on a TTY, repaints in place using ANSI cursor
+// movement.
+type lineRenderer struct {
+	pbs        []*pb.ProgressBar
+	maxLeft    int
+	termWidth  int
+	widthDelta int
+	responsive bool
+
+	lastRender []byte
+}
+
+func newLineRenderer(conf Config, pbs []*pb.ProgressBar, maxLeft, termWidth int) *lineRenderer {
+	r := &lineRenderer{
+		pbs:        pbs,
+		maxLeft:    maxLeft,
+		termWidth:  termWidth,
+		responsive: conf.UIMode.String == UIModeResponsive.String(),
+	}
+	if conf.UIMode.String == UIModeCompact.String() {
+		r.widthDelta = -pb.DefaultWidth
+	}
+	return r
+}
+
+func (r *lineRenderer) Resize(termWidth, _ int) {
+	r.termWidth = termWidth
+}
+
+func (r *lineRenderer) Render(goBack bool) {
+	barText, longestLine := renderMultipleBars(stdoutTTY, goBack, r.maxLeft, r.widthDelta, r.pbs)
+	if r.responsive {
+		// -1 to allow some "breathing room" near the edge
+		r.widthDelta = r.termWidth - longestLine - 1
+	}
+	r.lastRender = []byte(barText)
+	outMutex.Lock()
+	_, _ = stdout.Writer.Write(r.lastRender)
+	outMutex.Unlock()
+}
+
+func (r *lineRenderer) Stop() {}
+
 //TODO: show other information here?
 //TODO: add a no-progress option that will disable these
 //TODO: don't use global variables...
@@ -200,15 +265,25 @@ func showProgress(
 		return
 	}
 
+	// Derived so the interactive dashboard's 'q' keybinding can end the
+	// progress display the same way ctx.Done() does, without waiting for
+	// whoever owns ctx to cancel it.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	pbs := []*pb.ProgressBar{execScheduler.GetInitProgressBar()}
 	for _, s := range execScheduler.GetExecutors() {
 		pbs = append(pbs, s.GetProgress())
 	}
 
-	termWidth, _, err := terminal.GetSize(int(os.Stdout.Fd()))
+	theme := progressTheme(conf)
+	for _, p := range pbs {
+		p.Modify(pb.WithTheme(theme))
+	}
+
+	termWidth, termHeight, err := term.GetSize(int(os.Stdout.Fd()))
 	if err != nil {
 		logger.WithError(err).Warn("error getting terminal size")
-		termWidth = 80 // TODO: something safer, return error?
 	}
 
 	// Get the longest left side string length, to align progress bars
@@ -221,30 +296,64 @@ func showProgress(
 	// Limit to maximum left text length
 	maxLeft := int(lib.Min(leftLen, maxLeftLength))
 
-	var widthDelta int
-	var progressBarsLastRender []byte
-	// default responsive render
-	renderProgressBars := func(goBack bool) {
-		barText, longestLine := renderMultipleBars(stdoutTTY, goBack, maxLeft, widthDelta, pbs)
-		// -1 to allow some "breathing room" near the edge
-		widthDelta = termWidth - longestLine - 1
-		progressBarsLastRender = []byte(barText)
+	// conf.ProgressFormat (--progress-format) and conf.ProgressSocket
+	// (--progress-socket) are plumbed in as null.String config fields,
+	// same as conf.UIMode above; see progressFormatFlagSet.
+	if conf.ProgressFormat.Valid && conf.ProgressFormat.String == progressFormatNone {
+		return
 	}
-
-	if conf.UIMode.String == UIModeCompact.String() {
-		widthDelta = -pb.DefaultWidth
+	jsonStdout := conf.ProgressFormat.Valid && conf.ProgressFormat.String == progressFormatJSON
+	socketPath := conf.ProgressSocket.String
+	hasSocket := conf.ProgressSocket.Valid && socketPath != ""
+
+	// visible is whatever's drawn for a human: nothing when
+	// --progress-format=json claims stdout for the JSON stream instead,
+	// otherwise the usual interactive-or-line choice.
+	var visible ProgressRenderer
+	switch {
+	case jsonStdout:
+	case conf.UIMode.String == UIModeInteractive.String() && stdoutTTY:
+		visible = newInteractiveRenderer(execScheduler, pbs, maxLeft, termWidth, termHeight, logger, cancel)
+	default:
+		visible = newLineRenderer(conf, pbs, maxLeft, termWidth)
 	}
 
-	if conf.UIMode.String != UIModeResponsive.String() {
-		renderProgressBars = func(goBack bool) {
-			barText, _ := renderMultipleBars(stdoutTTY, goBack, maxLeft, widthDelta, pbs)
-			progressBarsLastRender = []byte(barText)
+	var renderer ProgressRenderer
+	switch {
+	case jsonStdout || hasSocket:
+		writers := make([]io.Writer, 0, 2)
+		var listener net.Listener
+		if jsonStdout {
+			writers = append(writers, os.Stdout)
+		}
+		if hasSocket {
+			sockets, l, err := newSocketBroadcaster(socketPath)
+			if err != nil {
+				logger.WithError(err).Error("could not start the progress socket")
+				return
+			}
+			writers = append(writers, sockets)
+			listener = l
+		}
+		sr := newStreamRenderer(execScheduler, io.MultiWriter(writers...), listener, logger)
+		if visible != nil {
+			renderer = newCombinedRenderer(visible, sr)
+		} else {
+			renderer = sr
 		}
+	default:
+		renderer = visible
 	}
 
-	printProgressBars := func() {
-		_, _ = stdout.Writer.Write(progressBarsLastRender)
+	// Only the line renderer gets keystroke controls: the interactive
+	// dashboard already owns stdin with its own keybindings, and a
+	// --progress-format=json/--progress-socket consumer expects stdin
+	// left alone rather than silently switched to raw mode.
+	stopKeys := func() {}
+	if _, isLine := renderer.(*lineRenderer); isLine && stdoutTTY {
+		stopKeys = startKeyListener(execScheduler, logger, cancel)
 	}
+	defer stopKeys()
 
 	//TODO: make configurable?
 	updateFreq := 1 * time.Second
@@ -266,19 +375,18 @@ func showProgress(
 			// the done context, so that the correct status symbol is
 			// outputted for each progress bar.
 			time.Sleep(50 * time.Millisecond)
-			renderProgressBars(false)
-			printProgressBars()
+			renderer.Render(false)
+			renderer.Stop()
 			return
 		case <-ticker.C:
 			// Optional "polling" method, platform dependent.
 			termWidth, _, _ = GetTermSize(fd, termWidth)
+			renderer.Resize(termWidth, termHeight)
 		case <-sigwinch:
 			// More efficient SIGWINCH method on *nix.
-			termWidth, _, _ = terminal.GetSize(fd)
+			termWidth, termHeight, _ = term.GetSize(fd)
+			renderer.Resize(termWidth, termHeight)
 		}
-		renderProgressBars(true)
-		outMutex.Lock()
-		printProgressBars()
-		outMutex.Unlock()
+		renderer.Render(true)
 	}
 }
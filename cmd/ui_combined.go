@@ -0,0 +1,50 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+// combinedRenderer fans Render/Resize/Stop out to every renderer it wraps,
+// so --progress-socket can run a streamRenderer alongside the line or
+// interactive renderer instead of replacing it.
+type combinedRenderer struct {
+	renderers []ProgressRenderer
+}
+
+func newCombinedRenderer(renderers ...ProgressRenderer) *combinedRenderer {
+	return &combinedRenderer{renderers: renderers}
+}
+
+func (c *combinedRenderer) Render(goBack bool) {
+	for _, r := range c.renderers {
+		r.Render(goBack)
+	}
+}
+
+func (c *combinedRenderer) Resize(termWidth, termHeight int) {
+	for _, r := range c.renderers {
+		r.Resize(termWidth, termHeight)
+	}
+}
+
+func (c *combinedRenderer) Stop() {
+	for _, r := range c.renderers {
+		r.Stop()
+	}
+}
@@ -0,0 +1,421 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/loadimpact/k6/core/local"
+	"github.com/loadimpact/k6/ui/pb"
+)
+
+// maxLogLines is how many of the most recent log lines are kept around for
+// the interactive dashboard's log pane.
+const maxLogLines = 200
+
+// logPaneLines is how many of the most recent log lines are shown at once.
+const logPaneLines = 8
+
+// keyReadTimeout bounds how long readKeys blocks on a single stdin read, so
+// it can notice Stop() within a fraction of a second instead of staying
+// parked in a read syscall until the next keystroke arrives.
+const keyReadTimeout = 200 * time.Millisecond
+
+// pane identifies which section of the interactive dashboard currently has
+// keyboard focus; tab cycles between them.
+type pane uint8
+
+const (
+	paneExecutors pane = iota
+	paneLog
+)
+
+// interactiveRenderer is the ProgressRenderer used for UIModeInteractive: a
+// full-screen, keyboard-driven dashboard with a scrollable executor list, a
+// live log tail and a handful of runtime controls. It takes over the whole
+// terminal for as long as it's active, repainting the full frame on every
+// Render instead of relying on the line-based cursor tricks in
+// renderMultipleBars.
+type interactiveRenderer struct {
+	execScheduler *local.ExecutionScheduler
+	pbs           []*pb.ProgressBar
+	logger        *logrus.Logger
+	// cancel ends showProgress's own polling loop early, the same way the
+	// caller's ctx.Done() would; it's what 'q' triggers.
+	cancel context.CancelFunc
+
+	maxLeft               int
+	termWidth, termHeight int
+
+	mu        sync.Mutex
+	focusPane pane
+	focusIdx  int
+	filter    string
+	filtering bool
+	paused    bool
+
+	logLines []string
+	logHook  *logPaneHook
+
+	rawState *terminal.State
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newInteractiveRenderer(
+	execScheduler *local.ExecutionScheduler, pbs []*pb.ProgressBar,
+	maxLeft, termWidth, termHeight int, logger *logrus.Logger, cancel context.CancelFunc,
+) *interactiveRenderer {
+	r := &interactiveRenderer{
+		execScheduler: execScheduler,
+		pbs:           pbs,
+		logger:        logger,
+		cancel:        cancel,
+		maxLeft:       maxLeft,
+		termWidth:     termWidth,
+		termHeight:    termHeight,
+		done:          make(chan struct{}),
+	}
+
+	if state, err := terminal.MakeRaw(int(os.Stdin.Fd())); err != nil {
+		logger.WithError(err).Warn("could not switch the terminal to raw mode, keybindings will be unavailable")
+	} else {
+		r.rawState = state
+	}
+
+	r.logHook = newLogPaneHook(r)
+	logger.AddHook(r.logHook)
+
+	go r.readKeys()
+
+	// Hide the cursor for the duration of the dashboard; Stop() restores it.
+	fprintf(stdout, "\x1b[?25l")
+
+	return r
+}
+
+// logPaneHook implements logrus.Hook, copying formatted log lines into the
+// dashboard's log pane. The logger's own output isn't redirected, so every
+// entry is still written there as well.
+type logPaneHook struct {
+	r *interactiveRenderer
+}
+
+func newLogPaneHook(r *interactiveRenderer) *logPaneHook {
+	return &logPaneHook{r: r}
+}
+
+func (h *logPaneHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *logPaneHook) Fire(entry *logrus.Entry) error {
+	line := fmt.Sprintf("[%s] %s", entry.Level.String(), entry.Message)
+	h.r.mu.Lock()
+	h.r.logLines = append(h.r.logLines, line)
+	if len(h.r.logLines) > maxLogLines {
+		h.r.logLines = h.r.logLines[len(h.r.logLines)-maxLogLines:]
+	}
+	h.r.mu.Unlock()
+	return nil
+}
+
+func (r *interactiveRenderer) Resize(termWidth, termHeight int) {
+	r.mu.Lock()
+	r.termWidth = termWidth
+	r.termHeight = termHeight
+	r.mu.Unlock()
+}
+
+// readKeys runs for the lifetime of the dashboard, translating raw stdin
+// bytes into the keybindings described in the dashboard's footer. Reads
+// are gated behind pollStdinReadable instead of a bare blocking Read, so
+// the goroutine notices r.done and exits soon after Stop() instead of
+// staying parked until the next keystroke (which, for a piped/closed
+// stdin, may never come).
+func (r *interactiveRenderer) readKeys() {
+	buf := make([]byte, 1)
+	fd := int(os.Stdin.Fd())
+	for {
+		select {
+		case <-r.done:
+			return
+		default:
+		}
+
+		ready, err := pollStdinReadable(fd, keyReadTimeout)
+		if err != nil {
+			return
+		}
+		if !ready {
+			continue
+		}
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		if n == 0 {
+			continue
+		}
+		r.handleKey(buf[0])
+	}
+}
+
+func (r *interactiveRenderer) handleKey(b byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.filtering {
+		switch b {
+		case '\r', '\n':
+			r.filtering = false
+		case 127, 8: // backspace
+			if len(r.filter) > 0 {
+				r.filter = r.filter[:len(r.filter)-1]
+			}
+		case 27: // esc
+			r.filtering = false
+			r.filter = ""
+		default:
+			r.filter += string(b)
+		}
+		return
+	}
+
+	switch b {
+	case 'q':
+		r.cancel()
+	case 'p':
+		r.togglePause()
+	case '+', '=', '-':
+		r.scaleFocusedVUs(b == '+' || b == '=')
+	case '/':
+		r.filtering = true
+		r.filter = ""
+	case '\t':
+		if r.focusPane == paneExecutors {
+			r.focusPane = paneLog
+		} else {
+			r.focusPane = paneExecutors
+		}
+	case 'j':
+		if r.focusIdx < len(r.pbs)-1 {
+			r.focusIdx++
+		}
+	case 'k':
+		if r.focusIdx > 0 {
+			r.focusIdx--
+		}
+	}
+}
+
+// togglePause pauses or resumes the whole test via the ExecutionScheduler's
+// runtime control API, and updates r.paused so Render reflects it. Callers
+// must hold r.mu.
+func (r *interactiveRenderer) togglePause() {
+	var err error
+	if r.execScheduler.IsPaused() {
+		err = r.execScheduler.Resume()
+	} else {
+		err = r.execScheduler.Pause()
+	}
+	if err != nil {
+		r.logger.WithError(err).Warn("could not toggle pause")
+		return
+	}
+	r.paused = r.execScheduler.IsPaused()
+}
+
+// focusedExecutorIndex translates r.focusIdx, which indexes into the
+// current (possibly filtered) visible bar list, into an index into
+// execScheduler.GetExecutors(). It returns false if the focused bar is the
+// init progress bar, which isn't backed by an executor. Callers must hold
+// r.mu.
+func (r *interactiveRenderer) focusedExecutorIndex() (int, bool) {
+	pbs := r.visiblePbsLocked()
+	if r.focusIdx < 0 || r.focusIdx >= len(pbs) {
+		return 0, false
+	}
+	target := pbs[r.focusIdx]
+	for i, p := range r.pbs {
+		if p == target {
+			// r.pbs[0] is the init progress bar; the executors start at 1,
+			// in the same order ExecutionScheduler indexes them.
+			return i - 1, i > 0
+		}
+	}
+	return 0, false
+}
+
+// scaleFocusedVUs increases (up) or decreases the number of active VUs on
+// the focused executor by one, via the ExecutionScheduler's runtime
+// control API. It's a no-op if the focus is on the init bar or the
+// focused executor doesn't support runtime VU scaling. Callers must hold
+// r.mu.
+func (r *interactiveRenderer) scaleFocusedVUs(up bool) {
+	idx, ok := r.focusedExecutorIndex()
+	if !ok {
+		return
+	}
+	vus, err := r.execScheduler.GetVUs(idx)
+	if err != nil {
+		r.logger.WithError(err).Debug("could not get the current number of VUs")
+		return
+	}
+	if up {
+		vus++
+	} else {
+		vus--
+	}
+	if vus < 0 {
+		vus = 0
+	}
+	if err := r.execScheduler.SetVUs(idx, vus); err != nil {
+		r.logger.WithError(err).Warn("could not change the number of VUs")
+	}
+}
+
+func (r *interactiveRenderer) Render(goBack bool) {
+	r.mu.Lock()
+	termHeight, filtering, filter, paused := r.termHeight, r.filtering, r.filter, r.paused
+	// Clamp focusIdx here (rather than in handleKey) so it stays valid as
+	// the filter narrows and widens the visible list out from under it.
+	pbs := r.visiblePbsLocked()
+	if r.focusIdx >= len(pbs) {
+		r.focusIdx = len(pbs) - 1
+	}
+	if r.focusIdx < 0 {
+		r.focusIdx = 0
+	}
+	focusIdx := r.focusIdx
+	logLines := append([]string(nil), r.logLines...)
+	r.mu.Unlock()
+
+	var b strings.Builder
+	// Clear the screen and move the cursor home; this renderer owns the
+	// whole terminal, unlike the line-based renderer.
+	b.WriteString("\x1b[2J\x1b[H")
+	b.WriteString("k6 interactive dashboard")
+	if paused {
+		b.WriteString("  [PAUSED]")
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(r.renderBars(pbs, focusIdx, termHeight))
+
+	b.WriteString("\n--- log ---\n")
+	start := 0
+	if len(logLines) > logPaneLines {
+		start = len(logLines) - logPaneLines
+	}
+	for _, line := range logLines[start:] {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\nq quit  p pause  +/- scale VUs  / filter  tab switch pane  j/k move focus")
+	if filtering {
+		fmt.Fprintf(&b, "  filter: %s", filter)
+	}
+
+	outMutex.Lock()
+	_, _ = os.Stdout.WriteString(b.String())
+	outMutex.Unlock()
+}
+
+// visiblePbsLocked is visiblePbs for callers that already hold r.mu.
+func (r *interactiveRenderer) visiblePbsLocked() []*pb.ProgressBar {
+	if r.filter == "" {
+		return r.pbs
+	}
+	filtered := make([]*pb.ProgressBar, 0, len(r.pbs))
+	for _, p := range r.pbs {
+		if strings.Contains(strings.ToLower(p.Left()), strings.ToLower(r.filter)) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// renderBars draws one line per bar in pbs, highlighting the one under
+// focus and scrolling the window so that line stays visible when there
+// are more bars than termHeight leaves room for.
+func (r *interactiveRenderer) renderBars(pbs []*pb.ProgressBar, focusIdx, termHeight int) string {
+	// Lines reserved by the header, log pane and footer around the bar
+	// list; see Render for the exact layout they're drawn in.
+	const reservedLines = 2 + 2 + logPaneLines + 2
+	available := termHeight - reservedLines
+	if available < 1 {
+		available = 1
+	}
+
+	start := 0
+	if len(pbs) > available {
+		if focusIdx >= available {
+			start = focusIdx - available + 1
+		}
+		if start+available > len(pbs) {
+			start = len(pbs) - available
+		}
+	}
+	end := start + available
+	if end > len(pbs) {
+		end = len(pbs)
+	}
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		rend := pbs[i].Render(r.maxLeft, 0)
+		line := fmt.Sprintf("%-*s %s %s", r.maxLeft, rend.Left, rend.StatusGlyph(), rend.Bar())
+		if len(rend.Right) > 0 {
+			line += " " + strings.Join(rend.Right, " ")
+		}
+		if i == focusIdx {
+			// Reverse video to mark the focused line.
+			line = "\x1b[7m> " + line + "\x1b[0m"
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (r *interactiveRenderer) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.done)
+		if r.rawState != nil {
+			_ = terminal.Restore(int(os.Stdin.Fd()), r.rawState)
+		}
+		fprintf(stdout, "\x1b[?25h") // show the cursor again
+	})
+}
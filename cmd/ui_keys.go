@@ -0,0 +1,136 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/loadimpact/k6/core/local"
+)
+
+// focusedExecutorIdx is the executor index `[`/`]` scale the VUs of in the
+// plain, line-based progress view. Unlike the interactive dashboard, this
+// view has no per-executor focus/selection UI, so it always targets the
+// first executor; if that one doesn't support runtime VU scaling, the keys
+// only log a warning. Most tests configure just one executor.
+const focusedExecutorIdx = 0
+
+// ctrlC is the byte MakeRaw'd stdin delivers for Ctrl-C. Raw mode clears
+// ISIG, so the terminal driver no longer turns it into a SIGINT the way it
+// would outside raw mode. handleProgressKey cancels showProgress's context
+// on it instead, which ends the progress display and restores the
+// terminal's normal mode; it doesn't send a SIGINT itself, so it takes a
+// second Ctrl-C to reach k6 as one.
+const ctrlC = 3
+
+// startKeyListener turns showProgress's line-based renderer into a control
+// surface too, not just a read-only view: it puts stdin into raw mode and
+// interprets space as pause/resume for every executor, `[`/`]` as
+// VU-scaling for the executor at focusedExecutorIdx, and Ctrl-C the same
+// way showProgress's own ctx.Done() is: as a request to stop. It's not
+// started for UIModeInteractive or the stream renderer, since the
+// dashboard already owns stdin with its own keybindings, and a stream
+// consumer expects stdin left alone.
+//
+// If stdin isn't a terminal (e.g. piped input, a CI job), MakeRaw fails
+// and this is a no-op - there's simply nothing to read keystrokes from.
+func startKeyListener(
+	execScheduler *local.ExecutionScheduler, logger *logrus.Logger, cancel context.CancelFunc,
+) (stop func()) {
+	fd := int(os.Stdin.Fd())
+	state, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			ready, perr := pollStdinReadable(fd, keyReadTimeout)
+			if perr != nil {
+				return
+			}
+			if !ready {
+				continue
+			}
+
+			n, rerr := os.Stdin.Read(buf)
+			if rerr != nil {
+				return
+			}
+			if n == 0 {
+				continue
+			}
+			handleProgressKey(execScheduler, logger, cancel, buf[0])
+		}
+	}()
+
+	return func() {
+		close(done)
+		_ = terminal.Restore(fd, state)
+	}
+}
+
+func handleProgressKey(
+	execScheduler *local.ExecutionScheduler, logger *logrus.Logger, cancel context.CancelFunc, b byte,
+) {
+	switch b {
+	case ctrlC:
+		cancel()
+	case ' ':
+		if execScheduler.IsPaused() {
+			if err := execScheduler.Resume(); err != nil {
+				logger.WithError(err).Warn("could not resume the test")
+			}
+		} else if err := execScheduler.Pause(); err != nil {
+			logger.WithError(err).Warn("could not pause the test")
+		}
+	case '[', ']':
+		vus, err := execScheduler.GetVUs(focusedExecutorIdx)
+		if err != nil {
+			logger.WithError(err).Debug("could not get the current number of VUs")
+			return
+		}
+		if b == '[' {
+			vus--
+		} else {
+			vus++
+		}
+		if vus < 0 {
+			vus = 0
+		}
+		if err := execScheduler.SetVUs(focusedExecutorIdx, vus); err != nil {
+			logger.WithError(err).Warn("could not change the number of VUs")
+		}
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// +build !windows
+
+package cmd
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// pollStdinReadable reports whether a read from fd would return data
+// within timeout, without blocking past it. It exists because
+// os.Stdin.SetReadDeadline returns "file type does not support deadline"
+// even when stdin is a real pty - a tty's fd isn't integrated with the Go
+// runtime's netpoller the way a socket is - so a plain Read would
+// otherwise block indefinitely and never notice a closed done channel.
+// poll(2), unlike the netpoller, works on a tty fd directly.
+func pollStdinReadable(fd int, timeout time.Duration) (bool, error) {
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+	for {
+		n, err := unix.Poll(fds, int(timeout/time.Millisecond))
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		return n > 0, nil
+	}
+}
@@ -0,0 +1,33 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import "time"
+
+// pollStdinReadable always reports stdin as readable on Windows: console
+// handles don't support the poll(2)-based readiness check ui_keys_poll_unix.go
+// uses, so readKeys/startKeyListener fall back to a plain blocking Read
+// there, same as before this fix. In practice this means Stop() may wait
+// for one more keystroke to arrive on Windows before the goroutine exits,
+// a known limitation.
+func pollStdinReadable(int, time.Duration) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,193 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/loadimpact/k6/core/local"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/ui/pb"
+)
+
+// Supported values for --progress-format.
+const (
+	progressFormatANSI = "ansi"
+	progressFormatJSON = "json"
+	progressFormatNone = "none"
+)
+
+// progressFormatFlagSet returns the --progress-format and --progress-socket
+// flags; the run command merges these into its own flag set alongside the
+// rest of Config's flags.
+func progressFormatFlagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.String("progress-format", progressFormatANSI,
+		"how to render progress updates: ansi, json or none")
+	flags.String("progress-socket", "",
+		"Unix socket path to additionally stream progress updates to, as newline-delimited JSON")
+	return flags
+}
+
+// progressEvent is one line of the newline-delimited JSON progress stream
+// emitted by streamRenderer, one per progress bar per tick.
+type progressEvent struct {
+	Executor   string    `json:"executor"`
+	Left       string    `json:"left"`
+	Progress   float64   `json:"progress"`
+	Status     string    `json:"status"`
+	RightCols  []string  `json:"right_cols"`
+	Iterations int64     `json:"iterations"`
+	VUs        int64     `json:"vus"`
+	Duration   float64   `json:"duration"` // seconds
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// socketBroadcaster fans out every Write to all currently-connected Unix
+// socket readers, so multiple wrapping tools (CI, a dashboard, ...) can
+// attach to the same progress stream at once. Readers that disconnect are
+// dropped on the next failed write.
+type socketBroadcaster struct {
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func (b *socketBroadcaster) add(conn net.Conn) {
+	b.mu.Lock()
+	b.conns = append(b.conns, conn)
+	b.mu.Unlock()
+}
+
+func (b *socketBroadcaster) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	live := b.conns[:0]
+	for _, conn := range b.conns {
+		if _, err := conn.Write(p); err == nil {
+			live = append(live, conn)
+		} else {
+			_ = conn.Close()
+		}
+	}
+	b.conns = live
+	return len(p), nil
+}
+
+// newSocketBroadcaster binds a Unix socket at socketPath and returns a
+// writer that fans out everything written to it to every connection
+// accepted on that socket, plus the listener so the caller can close it
+// once done. It's split out from streamRenderer so --progress-socket can
+// be combined with other writers (e.g. stdout, for --progress-format=json)
+// instead of being the renderer's only possible destination.
+func newSocketBroadcaster(socketPath string) (*socketBroadcaster, net.Listener, error) {
+	// Unlink a stale socket left behind by a previous run, same as most
+	// daemons that bind to a Unix socket path.
+	_ = os.Remove(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	sockets := &socketBroadcaster{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return // listener closed in Stop()
+			}
+			sockets.add(conn)
+		}
+	}()
+	return sockets, listener, nil
+}
+
+// streamRenderer is the ProgressRenderer behind --progress-format=json and
+// --progress-socket: instead of painting bars to the terminal, it emits one
+// JSON object per progress bar per tick to w, which may combine stdout
+// and/or every reader connected to a Unix socket (see newSocketBroadcaster
+// and io.MultiWriter).
+type streamRenderer struct {
+	execScheduler *local.ExecutionScheduler
+	listener      net.Listener
+	logger        *logrus.Logger
+	encoder       *json.Encoder
+}
+
+// newStreamRenderer builds a streamRenderer writing to w. listener, if
+// non-nil, is closed in Stop() - it's the socket listener backing (part
+// of) w, if --progress-socket is in use.
+func newStreamRenderer(
+	execScheduler *local.ExecutionScheduler, w io.Writer, listener net.Listener, logger *logrus.Logger,
+) *streamRenderer {
+	return &streamRenderer{
+		execScheduler: execScheduler,
+		listener:      listener,
+		logger:        logger,
+		encoder:       json.NewEncoder(w),
+	}
+}
+
+func (r *streamRenderer) Resize(int, int) {}
+
+func (r *streamRenderer) Render(bool) {
+	now := time.Now()
+	r.emit("init", r.execScheduler.GetInitProgressBar(), nil, now)
+	for _, ex := range r.execScheduler.GetExecutors() {
+		r.emit(ex.GetConfig().GetName(), ex.GetProgress(), ex, now)
+	}
+}
+
+// emit renders bar and encodes it as a progressEvent named name. ex is nil
+// for the init progress bar, which isn't backed by an executor and so has
+// no iteration/VU/duration stats to report.
+func (r *streamRenderer) emit(name string, bar *pb.ProgressBar, ex lib.Executor, now time.Time) {
+	rend := bar.Render(0, 0)
+	event := progressEvent{
+		Executor:  name,
+		Left:      rend.Left,
+		Progress:  rend.Progress,
+		Status:    rend.Status.String(),
+		RightCols: rend.Right,
+		Timestamp: now,
+	}
+	if ex != nil {
+		event.Iterations = ex.GetCurrentIterations()
+		event.VUs = ex.GetCurrentVUs()
+		event.Duration = ex.GetCurrentDuration().Seconds()
+	}
+	if err := r.encoder.Encode(event); err != nil {
+		r.logger.WithError(err).Debug("could not write a progress event")
+	}
+}
+
+func (r *streamRenderer) Stop() {
+	if r.listener != nil {
+		_ = r.listener.Close()
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/loadimpact/k6/ui/pb"
+)
+
+// progressThemeFlagSet returns the --progress-theme flag; the run command
+// merges this into its own flag set alongside the rest of Config's flags,
+// the same way progressFormatFlagSet does for --progress-format.
+func progressThemeFlagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.String("progress-theme", "",
+		"glyph set to render progress bars with: unicode, ascii or blocks (default: auto-detected)")
+	return flags
+}
+
+// progressTheme resolves the pb.Theme to render progress bars with, given
+// --progress-theme (conf.ProgressTheme) and the current environment. With
+// no explicit choice, it falls back to ThemeASCII whenever noColor is set
+// or the locale doesn't look like it can render UTF-8, since box-drawing
+// and block glyphs tend to come out as mangled bytes in those cases.
+func progressTheme(conf Config) *pb.Theme {
+	if conf.ProgressTheme.Valid && conf.ProgressTheme.String != "" {
+		if theme, ok := pb.Themes[conf.ProgressTheme.String]; ok {
+			return theme
+		}
+	}
+
+	if noColor || !localeHasUTF8() {
+		return pb.ThemeASCII
+	}
+	return pb.ThemeUnicode
+}
+
+// localeHasUTF8 reports whether LC_ALL/LC_CTYPE/LANG name a UTF-8 locale,
+// the same variables the C library consults to decide how to render text.
+func localeHasUTF8() bool {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return strings.Contains(strings.ToUpper(v), "UTF-8") || strings.Contains(strings.ToUpper(v), "UTF8")
+		}
+	}
+	// No locale env vars set at all - assume a minimal/POSIX locale, which
+	// is not UTF-8 capable.
+	return false
+}
@@ -0,0 +1,135 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package local implements an ExecutionScheduler that runs executors
+// in the current process, as opposed to distributing them across a
+// cluster.
+package local
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/ui/pb"
+)
+
+// ExecutionScheduler schedules and runs the executors configured for a
+// test, and tracks their overall progress.
+type ExecutionScheduler struct {
+	mutex sync.RWMutex
+
+	initProgress *pb.ProgressBar
+	executors    []lib.Executor
+	paused       bool
+}
+
+// GetInitProgressBar returns the progress bar tracking test setup, i.e.
+// everything that happens before any executor starts running.
+func (e *ExecutionScheduler) GetInitProgressBar() *pb.ProgressBar {
+	return e.initProgress
+}
+
+// GetExecutors returns the configured executors, in the order they run.
+func (e *ExecutionScheduler) GetExecutors() []lib.Executor {
+	return e.executors
+}
+
+// IsPaused reports whether the scheduler is currently paused.
+func (e *ExecutionScheduler) IsPaused() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.paused
+}
+
+// Pause signals every executor's PauseGate and recolors its progress bar
+// to reflect the paused state. Whether this actually stops a new
+// iteration from starting depends on the executor's own run loop calling
+// GetPauseGate().Wait() between iterations, as documented on
+// lib.Executor.GetPauseGate - ExecutionScheduler itself has no iteration
+// loop to stop. It's a no-op if already paused.
+func (e *ExecutionScheduler) Pause() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.paused {
+		return nil
+	}
+	e.paused = true
+	for _, ex := range e.executors {
+		ex.GetPauseGate().Pause()
+		ex.GetProgress().Modify(pb.WithStatus(pb.Paused))
+	}
+	return nil
+}
+
+// Resume resumes every executor previously paused with Pause. It's a
+// no-op if the scheduler isn't paused.
+func (e *ExecutionScheduler) Resume() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !e.paused {
+		return nil
+	}
+	e.paused = false
+	for _, ex := range e.executors {
+		ex.GetPauseGate().Resume()
+		ex.GetProgress().Modify(pb.WithStatus(pb.Running))
+	}
+	return nil
+}
+
+// vuController looks up the executor at executorIdx and checks that it
+// supports runtime VU scaling.
+func (e *ExecutionScheduler) vuController(executorIdx int) (lib.VUController, error) {
+	if executorIdx < 0 || executorIdx >= len(e.executors) {
+		return nil, fmt.Errorf("no executor at index %d", executorIdx)
+	}
+	vc, ok := e.executors[executorIdx].(lib.VUController)
+	if !ok {
+		return nil, fmt.Errorf(
+			"executor %s doesn't support changing the number of VUs while running",
+			e.executors[executorIdx].GetConfig().GetName(),
+		)
+	}
+	return vc, nil
+}
+
+// GetVUs returns the current number of active VUs for the executor at the
+// given index.
+func (e *ExecutionScheduler) GetVUs(executorIdx int) (int64, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	if executorIdx < 0 || executorIdx >= len(e.executors) {
+		return 0, fmt.Errorf("no executor at index %d", executorIdx)
+	}
+	return e.executors[executorIdx].GetCurrentVUs(), nil
+}
+
+// SetVUs changes the number of active VUs for the executor at the given
+// index, if it supports runtime VU scaling.
+func (e *ExecutionScheduler) SetVUs(executorIdx int, vus int64) error {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	vc, err := e.vuController(executorIdx)
+	if err != nil {
+		return err
+	}
+	return vc.SetVUs(vus)
+}
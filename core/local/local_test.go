@@ -0,0 +1,111 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package local
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/ui/pb"
+)
+
+// stubExecutor is a minimal lib.VUController used to exercise
+// ExecutionScheduler's Pause/Resume/SetVUs without a real executor.
+type stubExecutor struct {
+	name string
+	pg   *lib.PauseGate
+	bar  *pb.ProgressBar
+	vus  int64
+}
+
+func newStubExecutor(name string) *stubExecutor {
+	return &stubExecutor{name: name, pg: lib.NewPauseGate(), bar: pb.New()}
+}
+
+func (s *stubExecutor) GetConfig() lib.ExecutorConfig    { return stubConfig{s.name} }
+func (s *stubExecutor) GetProgress() *pb.ProgressBar      { return s.bar }
+func (s *stubExecutor) GetCurrentVUs() int64              { return atomic.LoadInt64(&s.vus) }
+func (s *stubExecutor) GetCurrentIterations() int64       { return 0 }
+func (s *stubExecutor) GetCurrentDuration() time.Duration { return 0 }
+func (s *stubExecutor) GetPauseGate() *lib.PauseGate       { return s.pg }
+func (s *stubExecutor) SetVUs(vus int64) error {
+	atomic.StoreInt64(&s.vus, vus)
+	return nil
+}
+
+type stubConfig struct{ name string }
+
+func (c stubConfig) GetName() string { return c.name }
+
+func TestExecutionSchedulerPauseResume(t *testing.T) {
+	t.Parallel()
+
+	ex := newStubExecutor("stub")
+	sched := &ExecutionScheduler{executors: []lib.Executor{ex}}
+
+	if sched.IsPaused() {
+		t.Fatal("new scheduler reports paused")
+	}
+
+	if err := sched.Pause(); err != nil {
+		t.Fatalf("Pause() returned %v", err)
+	}
+	if !sched.IsPaused() || !ex.pg.IsPaused() {
+		t.Fatal("Pause() did not pause the scheduler and its executors")
+	}
+	if ex.bar.Render(0, 0).Status != pb.Paused {
+		t.Fatal("Pause() did not recolor the executor's progress bar")
+	}
+
+	if err := sched.Resume(); err != nil {
+		t.Fatalf("Resume() returned %v", err)
+	}
+	if sched.IsPaused() || ex.pg.IsPaused() {
+		t.Fatal("Resume() did not resume the scheduler and its executors")
+	}
+	if ex.bar.Render(0, 0).Status != pb.Running {
+		t.Fatal("Resume() did not recolor the executor's progress bar")
+	}
+}
+
+func TestExecutionSchedulerSetVUs(t *testing.T) {
+	t.Parallel()
+
+	ex := newStubExecutor("stub")
+	sched := &ExecutionScheduler{executors: []lib.Executor{ex}}
+
+	if err := sched.SetVUs(0, 5); err != nil {
+		t.Fatalf("SetVUs() returned %v", err)
+	}
+	vus, err := sched.GetVUs(0)
+	if err != nil {
+		t.Fatalf("GetVUs() returned %v", err)
+	}
+	if vus != 5 {
+		t.Fatalf("GetVUs() = %d, want 5", vus)
+	}
+
+	if _, err := sched.GetVUs(1); err == nil {
+		t.Fatal("GetVUs() with an out-of-range index should return an error")
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package lib
+
+import (
+	"time"
+
+	"github.com/loadimpact/k6/ui/pb"
+)
+
+// Executor is implemented by every execution-scheduler executor
+// (ConstantVUs, RampingVUs, ConstantArrivalRate, ...); ExecutionScheduler
+// operates on a list of these without caring about the concrete type.
+type Executor interface {
+	// GetConfig returns the executor's config, identifying it among others.
+	GetConfig() ExecutorConfig
+	// GetProgress returns the progress bar tracking this executor's run.
+	GetProgress() *pb.ProgressBar
+	// GetCurrentVUs returns the number of currently active VUs.
+	GetCurrentVUs() int64
+	// GetCurrentIterations returns the number of iterations completed so far.
+	GetCurrentIterations() int64
+	// GetCurrentDuration returns how long the executor has been running.
+	GetCurrentDuration() time.Duration
+	// GetPauseGate returns the gate ExecutionScheduler.Pause/Resume
+	// signal. An executor's run loop is expected to call Wait() on it
+	// between iterations so that pausing actually stops new iterations
+	// from starting, not just recolors the progress bar - but that's a
+	// contract on the implementation's run loop, not something this
+	// interface can enforce by itself.
+	GetPauseGate() *PauseGate
+}
+
+// VUController is implemented by executors that support changing their
+// number of active VUs while running (e.g. RampingVUs, but not
+// ConstantArrivalRate). ExecutionScheduler.SetVUs type-asserts for this
+// instead of requiring it of every Executor.
+type VUController interface {
+	Executor
+	// SetVUs changes the number of currently active VUs.
+	SetVUs(vus int64) error
+}
+
+// ExecutorConfig is the minimal config every executor implementation
+// exposes.
+type ExecutorConfig interface {
+	GetName() string
+}
@@ -0,0 +1,74 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package lib
+
+import "sync"
+
+// PauseGate is a cooperative pause/resume signal an Executor can block on
+// between iterations. Unlike recoloring a progress bar, blocking in Wait
+// actually stops new iterations from starting while paused.
+type PauseGate struct {
+	mutex  sync.Mutex
+	cond   *sync.Cond
+	paused bool
+}
+
+// NewPauseGate returns a PauseGate that starts out unpaused.
+func NewPauseGate() *PauseGate {
+	pg := &PauseGate{}
+	pg.cond = sync.NewCond(&pg.mutex)
+	return pg
+}
+
+// Pause blocks future Wait calls until Resume is called. It's a no-op if
+// the gate is already paused.
+func (pg *PauseGate) Pause() {
+	pg.mutex.Lock()
+	defer pg.mutex.Unlock()
+	pg.paused = true
+}
+
+// Resume unblocks any goroutines currently parked in Wait. It's a no-op if
+// the gate isn't paused.
+func (pg *PauseGate) Resume() {
+	pg.mutex.Lock()
+	defer pg.mutex.Unlock()
+	pg.paused = false
+	pg.cond.Broadcast()
+}
+
+// Wait blocks the calling goroutine for as long as the gate is paused. An
+// Executor should call this between iterations, not in the middle of one,
+// so that an in-flight iteration is allowed to finish before pausing.
+func (pg *PauseGate) Wait() {
+	pg.mutex.Lock()
+	defer pg.mutex.Unlock()
+	for pg.paused {
+		pg.cond.Wait()
+	}
+}
+
+// IsPaused reports whether the gate is currently paused.
+func (pg *PauseGate) IsPaused() bool {
+	pg.mutex.Lock()
+	defer pg.mutex.Unlock()
+	return pg.paused
+}
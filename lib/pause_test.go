@@ -0,0 +1,75 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package lib
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPauseGateUnpausedDoesNotBlock(t *testing.T) {
+	t.Parallel()
+
+	pg := NewPauseGate()
+	done := make(chan struct{})
+	go func() {
+		pg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait blocked on an unpaused gate")
+	}
+}
+
+func TestPauseGateBlocksUntilResume(t *testing.T) {
+	t.Parallel()
+
+	pg := NewPauseGate()
+	pg.Pause()
+	if !pg.IsPaused() {
+		t.Fatal("IsPaused() = false after Pause()")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		pg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Wait returned before Resume was called")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	pg.Resume()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not unblock after Resume")
+	}
+	if pg.IsPaused() {
+		t.Fatal("IsPaused() = true after Resume()")
+	}
+}
@@ -0,0 +1,287 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package pb implements the progress bars k6 prints for the init and
+// per-executor progress during a test run.
+package pb
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultWidth is how wide a rendered progress bar is, excluding the caps
+// and the left/right side text, unless overridden by a widthDelta.
+const DefaultWidth = 40
+
+// Status represents the current state of whatever a ProgressBar is
+// tracking progress for.
+type Status int
+
+// Valid Status values.
+const (
+	Running Status = iota
+	Paused
+	Done
+	Interrupted
+)
+
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Paused:
+		return "paused"
+	case Done:
+		return "done"
+	case Interrupted:
+		return "interrupted"
+	default:
+		return "unknown"
+	}
+}
+
+// colorCode returns the ANSI SGR color code used to render the status
+// glyph, mirroring the colors already used for pass/fail output elsewhere
+// in k6 (green for success, red for failure, cyan while in progress).
+func (s Status) colorCode() string {
+	switch s {
+	case Done:
+		return "32" // green
+	case Interrupted:
+		return "31" // red
+	case Paused:
+		return "33" // yellow
+	default:
+		return "36" // cyan
+	}
+}
+
+// ProgressBar is a thread-safe progress indicator with left-side and
+// right-side text callbacks and a fill fraction, rendered by Render()
+// into display strings.
+type ProgressBar struct {
+	mutex sync.RWMutex
+
+	left     func() string
+	progress func() (progress float64, right []string)
+	hijack   func() string
+	status   Status
+	theme    *Theme
+}
+
+// ProgressBarOption configures a ProgressBar; see the With* functions.
+type ProgressBarOption func(*ProgressBar)
+
+// WithLeft sets the function that renders the bar's left-side text.
+func WithLeft(left func() string) ProgressBarOption {
+	return func(pb *ProgressBar) { pb.left = left }
+}
+
+// WithConstLeft sets a fixed left-side text.
+func WithConstLeft(left string) ProgressBarOption {
+	return WithLeft(func() string { return left })
+}
+
+// WithProgress sets the function that reports the current progress
+// fraction (0 to 1) and any right-side columns.
+func WithProgress(progress func() (float64, []string)) ProgressBarOption {
+	return func(pb *ProgressBar) { pb.progress = progress }
+}
+
+// WithConstProgress sets a fixed progress fraction and right-side columns.
+func WithConstProgress(progress float64, right ...string) ProgressBarOption {
+	return WithProgress(func() (float64, []string) { return progress, right })
+}
+
+// WithStatus sets the bar's status.
+func WithStatus(status Status) ProgressBarOption {
+	return func(pb *ProgressBar) { pb.status = status }
+}
+
+// WithHijack overrides the whole rendered line with the given function's
+// output, e.g. to show a setup error instead of a bar.
+func WithHijack(hijack func() string) ProgressBarOption {
+	return func(pb *ProgressBar) { pb.hijack = hijack }
+}
+
+// WithTheme sets the glyph set the bar is rendered with. A bar with a nil
+// theme (the zero value, as produced by New() without this option) renders
+// with ThemeUnicode.
+func WithTheme(theme *Theme) ProgressBarOption {
+	return func(pb *ProgressBar) { pb.theme = theme }
+}
+
+// New creates a new ProgressBar with the given options applied.
+func New(options ...ProgressBarOption) *ProgressBar {
+	bar := &ProgressBar{status: Running}
+	for _, option := range options {
+		option(bar)
+	}
+	return bar
+}
+
+// Modify applies options to an existing ProgressBar, e.g. to change its
+// status or progress function at runtime.
+func (pb *ProgressBar) Modify(options ...ProgressBarOption) {
+	pb.mutex.Lock()
+	defer pb.mutex.Unlock()
+	for _, option := range options {
+		option(pb)
+	}
+}
+
+// Left returns the bar's current left-side text.
+func (pb *ProgressBar) Left() string {
+	pb.mutex.RLock()
+	defer pb.mutex.RUnlock()
+	if pb.left == nil {
+		return ""
+	}
+	return pb.left()
+}
+
+// ProgressBarRender holds everything needed to print a single frame of a
+// ProgressBar: the Left text, the Right columns, and the Progress/Status
+// data needed to draw the bar itself or, for non-terminal consumers, to
+// serialize it directly instead of scraping Bar()'s rendered glyphs.
+type ProgressBarRender struct {
+	Left, Hijack string
+	Right        []string
+	Color        bool
+
+	// Progress is the fill fraction, from 0 to 1.
+	Progress float64
+	// Status is the bar's current state.
+	Status Status
+
+	width int
+	theme *Theme
+}
+
+// Render computes a frame for the current state of the bar. leftMax trims
+// (and ANSI-pads callers rely on) the left text to at most that many
+// runes; widthDelta grows or shrinks the bar from DefaultWidth, e.g. to
+// make room for a wide terminal or to fit a narrow one.
+func (pb *ProgressBar) Render(leftMax, widthDelta int) ProgressBarRender {
+	pb.mutex.RLock()
+	defer pb.mutex.RUnlock()
+
+	if pb.hijack != nil {
+		return ProgressBarRender{Hijack: pb.hijack()}
+	}
+
+	left := ""
+	if pb.left != nil {
+		left = pb.left()
+	}
+	if leftMax > 0 && len(left) > leftMax {
+		left = left[:leftMax-1] + "…"
+	}
+
+	var progress float64
+	var right []string
+	if pb.progress != nil {
+		progress, right = pb.progress()
+	}
+	if progress < 0 {
+		progress = 0
+	} else if progress > 1 {
+		progress = 1
+	}
+
+	width := DefaultWidth + widthDelta
+	if width < 10 {
+		width = 10
+	}
+
+	theme := pb.theme
+	if theme == nil {
+		theme = ThemeUnicode
+	}
+
+	return ProgressBarRender{
+		Left:     left,
+		Right:    right,
+		Status:   pb.status,
+		Progress: progress,
+		width:    width,
+		theme:    theme,
+	}
+}
+
+// Bar renders the fill portion of the bar, e.g. "[===>------]" with ThemeASCII.
+func (r ProgressBarRender) Bar() string {
+	if r.Hijack != "" {
+		return ""
+	}
+
+	theme := r.theme
+	if theme == nil {
+		theme = ThemeUnicode
+	}
+
+	filledCells := r.Progress * float64(r.width)
+	filled := int(filledCells)
+	if filled > r.width {
+		filled = r.width
+	}
+
+	var b strings.Builder
+	b.WriteRune(theme.LeftCap)
+	for i := 0; i < filled; i++ {
+		b.WriteRune(theme.Fill)
+	}
+	if filled < r.width && r.Status == Running {
+		if len(theme.SubCells) > 0 {
+			frac := filledCells - float64(filled)
+			if frac > 0 {
+				idx := int(frac * float64(len(theme.SubCells)))
+				if idx >= len(theme.SubCells) {
+					idx = len(theme.SubCells) - 1
+				}
+				b.WriteRune(theme.SubCells[idx])
+				filled++
+			}
+		} else {
+			b.WriteRune(theme.Head)
+			filled++
+		}
+	}
+	for i := filled; i < r.width; i++ {
+		b.WriteRune(theme.Empty)
+	}
+	b.WriteRune(theme.RightCap)
+	return b.String()
+}
+
+// StatusGlyph renders the bar's status glyph, colorized if Color is set.
+func (r ProgressBarRender) StatusGlyph() string {
+	theme := r.theme
+	if theme == nil {
+		theme = ThemeUnicode
+	}
+	glyph := string(theme.glyphFor(r.Status))
+	if !r.Color {
+		return glyph
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", r.Status.colorCode(), glyph)
+}
@@ -0,0 +1,81 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package pb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProgressBarRenderBarSubCells(t *testing.T) {
+	t.Parallel()
+
+	// At 0 progress no cell is covered at all, so the sub-cell fraction is
+	// exactly 0; at 0.05 the width of 10 cells leaves half of the first
+	// cell covered, which needs a sub-cell glyph.
+	const width = 10
+
+	bar := New(WithConstProgress(0, ""), WithTheme(ThemeBlocks))
+	bar.Modify(WithStatus(Running))
+	rend := bar.Render(0, width-DefaultWidth)
+
+	if strings.ContainsAny(rend.Bar(), string(ThemeBlocks.SubCells)) {
+		t.Errorf("Bar() at 0%% progress must not contain a sub-cell glyph, got %q", rend.Bar())
+	}
+
+	bar.Modify(WithConstProgress(0.05, ""))
+	rend = bar.Render(0, width-DefaultWidth)
+	if !strings.ContainsAny(rend.Bar(), string(ThemeBlocks.SubCells)) {
+		t.Errorf("Bar() at partial progress should contain a sub-cell glyph, got %q", rend.Bar())
+	}
+}
+
+func TestProgressBarRenderBarNoSubCellsWhenNotRunning(t *testing.T) {
+	t.Parallel()
+
+	// 0.51 of DefaultWidth leaves a partly covered cell at the edge. The
+	// last SubCells glyph is also ThemeBlocks' Fill, so only the partial
+	// ones are looked for.
+	bar := New(WithConstProgress(0.51, ""), WithTheme(ThemeBlocks), WithStatus(Done))
+	rend := bar.Render(0, 0)
+	partial := string(ThemeBlocks.SubCells[:len(ThemeBlocks.SubCells)-1])
+	if strings.ContainsAny(rend.Bar(), partial) {
+		t.Errorf("Bar() for a Done bar must not contain a sub-cell glyph, got %q", rend.Bar())
+	}
+}
+
+func TestThemeGlyphFor(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		status Status
+		want   rune
+	}{
+		{Running, ThemeUnicode.StatusRunning},
+		{Done, ThemeUnicode.StatusDone},
+		{Interrupted, ThemeUnicode.StatusFailed},
+	}
+	for _, tc := range cases {
+		if got := ThemeUnicode.glyphFor(tc.status); got != tc.want {
+			t.Errorf("glyphFor(%v) = %q, want %q", tc.status, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package pb
+
+// Theme describes the glyphs a ProgressBar is rendered with: the bar's
+// caps and fill, and the status indicators shown next to it. Swapping the
+// Theme a bar renders with doesn't change anything about how progress is
+// tracked, only how it looks - e.g. ThemeASCII exists so the bars stay
+// legible in places that can't render box-drawing characters, such as log
+// aggregators or older Windows consoles.
+type Theme struct {
+	Name string
+
+	LeftCap, RightCap rune
+	// Fill is used for fully-covered cells, Empty for not-yet-covered
+	// ones. Head is drawn at the current edge of the progress; themes
+	// that support sub-cell precision (ThemeBlocks) use SubCells at the
+	// edge instead.
+	Fill, Head, Empty rune
+	// SubCells are increasingly-full glyphs used to render the partial
+	// cell at the progress edge with finer precision than one rune per
+	// whole cell would allow. Left empty, the edge just uses Head.
+	SubCells []rune
+
+	StatusRunning, StatusPaused, StatusDone, StatusFailed rune
+}
+
+// ThemeUnicode is the default theme: solid blocks, no sub-cell precision.
+var ThemeUnicode = &Theme{
+	Name:          "unicode",
+	LeftCap:       '[',
+	RightCap:      ']',
+	Fill:          '█',
+	Head:          '█',
+	Empty:         ' ',
+	StatusRunning: '●',
+	StatusPaused:  '‖',
+	StatusDone:    '✓',
+	StatusFailed:  '✗',
+}
+
+// ThemeASCII sticks to 7-bit ASCII, for terminals, logs and Windows
+// consoles that mangle box-drawing and other Unicode glyphs.
+var ThemeASCII = &Theme{
+	Name:          "ascii",
+	LeftCap:       '[',
+	RightCap:      ']',
+	Fill:          '=',
+	Head:          '>',
+	Empty:         '-',
+	StatusRunning: 'r',
+	StatusPaused:  'p',
+	StatusDone:    '+',
+	StatusFailed:  'x',
+}
+
+// ThemeBlocks uses the eighth-wide block glyphs to render sub-cell
+// precision at the progress edge, the same trick schollz/progressbar
+// uses to make narrow terminal bars look smooth.
+var ThemeBlocks = &Theme{
+	Name:          "blocks",
+	LeftCap:       '[',
+	RightCap:      ']',
+	Fill:          '█',
+	Head:          '█',
+	Empty:         ' ',
+	SubCells:      []rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'},
+	StatusRunning: '●',
+	StatusPaused:  '‖',
+	StatusDone:    '✓',
+	StatusFailed:  '✗',
+}
+
+// Themes indexes the built-in themes by the name used for --progress-theme.
+var Themes = map[string]*Theme{
+	ThemeUnicode.Name: ThemeUnicode,
+	ThemeASCII.Name:   ThemeASCII,
+	ThemeBlocks.Name:  ThemeBlocks,
+}
+
+func (t *Theme) glyphFor(s Status) rune {
+	switch s {
+	case Paused:
+		return t.StatusPaused
+	case Done:
+		return t.StatusDone
+	case Interrupted:
+		return t.StatusFailed
+	default:
+		return t.StatusRunning
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package term provides small, cross-platform helpers around terminal size
+// detection and ANSI escape sequence support, so that callers (namely
+// cmd.consoleWriter and the progress bar renderers) don't have to hard-code
+// assumptions that only hold on *nix TTYs.
+package term
+
+import (
+	"os"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// DefaultWidth and DefaultHeight are used whenever no terminal size can be
+// determined at all, e.g. when stdout is redirected and /dev/tty isn't
+// reachable either, such as in restricted CI/container environments.
+const (
+	DefaultWidth  = 80
+	DefaultHeight = 25
+)
+
+// GetSize returns the terminal size for fd, falling back to querying
+// /dev/tty directly if fd itself doesn't refer to a terminal (e.g. stdout
+// was redirected to a file but a terminal is still attached), and finally
+// to DefaultWidth/DefaultHeight if neither works.
+func GetSize(fd int) (width, height int, err error) {
+	if w, h, gerr := terminal.GetSize(fd); gerr == nil {
+		return w, h, nil
+	}
+
+	if tty, terr := os.Open("/dev/tty"); terr == nil {
+		defer tty.Close() //nolint:errcheck
+		if w, h, gerr := terminal.GetSize(int(tty.Fd())); gerr == nil {
+			return w, h, nil
+		}
+	}
+
+	return DefaultWidth, DefaultHeight, errNoTerminal
+}
+
+var errNoTerminal = &sizeError{"could not determine the terminal size"}
+
+type sizeError struct{ msg string }
+
+func (e *sizeError) Error() string { return e.msg }
+
+// SupportsANSI reports whether output written to fd can be expected to
+// render ANSI escape sequences. On Windows this also has the side effect
+// of switching the console into virtual-terminal-processing mode, since
+// that's the only way to find out without actually trying to render.
+func SupportsANSI(fd uintptr) bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return enableANSI(fd)
+}